@@ -0,0 +1,122 @@
+package encoji
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Algo selects the compression codec applied to the clear text before it
+// is turned into variation selectors. Each payload byte costs 3-4 UTF-8
+// bytes on the wire, so compressing first meaningfully shrinks long
+// payloads.
+type Algo int
+
+const (
+	// CompressNone leaves the payload uncompressed. This is the default.
+	// The payload still carries the 1-byte algorithm tag, so its wire
+	// format differs from that of versions of encoji without compression
+	// support, and payloads written by those versions are not decoded.
+	CompressNone Algo = iota
+	CompressGzip
+	CompressZstd
+)
+
+// compressPayload compresses data with algo and prefixes the result with a
+// 1-byte algorithm tag, always, even for CompressNone. decompressPayload
+// reads that tag instead of sniffing the payload's content for a magic
+// string, which earlier versions did and which broke decoding of any
+// uncompressed clear text that happened to start with the same bytes.
+func compressPayload(algo Algo, data []byte) ([]byte, error) {
+	if algo == CompressNone {
+		return append([]byte{byte(CompressNone)}, data...), nil
+	}
+
+	var compressed []byte
+	switch algo {
+	case CompressGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("gzip compressing clear text: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("gzip compressing clear text: %w", err)
+		}
+		compressed = buf.Bytes()
+	case CompressZstd:
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, fmt.Errorf("zstd compressing clear text: %w", err)
+		}
+		defer enc.Close()
+		compressed = enc.EncodeAll(data, nil)
+	default:
+		return nil, fmt.Errorf("encoji: unknown compression algorithm %d", algo)
+	}
+
+	out := make([]byte, 0, 1+len(compressed))
+	out = append(out, byte(algo))
+	out = append(out, compressed...)
+	return out, nil
+}
+
+// decompressPayload reverses compressPayload, reading the 1-byte algorithm
+// tag every payload now carries instead of guessing whether data was
+// compressed.
+func decompressPayload(data []byte) ([]byte, error) {
+	if len(data) < 1 {
+		return nil, errors.New("encoji: empty payload")
+	}
+
+	algo := Algo(data[0])
+	compressed := data[1:]
+
+	switch algo {
+	case CompressNone:
+		return compressed, nil
+	case CompressGzip:
+		r, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, fmt.Errorf("gzip decompressing clear text: %w", err)
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case CompressZstd:
+		dec, err := zstd.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, fmt.Errorf("zstd decompressing clear text: %w", err)
+		}
+		defer dec.Close()
+		return io.ReadAll(dec)
+	default:
+		return nil, fmt.Errorf("encoji: unknown compression algorithm %d", algo)
+	}
+}
+
+// parseAlgo parses the -compress flag value accepted by Main.
+func parseAlgo(name string) (Algo, error) {
+	switch name {
+	case "", "none":
+		return CompressNone, nil
+	case "gzip":
+		return CompressGzip, nil
+	case "zstd":
+		return CompressZstd, nil
+	default:
+		return CompressNone, fmt.Errorf("encoji: unknown compression algorithm %q", name)
+	}
+}
+
+// WithCompression configures the smuggler to compress the clear text
+// before hiding it, and transparently decompress it on decode.
+func WithCompression(algo Algo) option {
+	return func(s *smuggler) error {
+		s.compression = algo
+		return nil
+	}
+}
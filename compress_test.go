@@ -0,0 +1,125 @@
+package encoji
+
+import (
+	"bytes"
+	"testing"
+)
+
+// representative inputs for the compression benchmark below.
+var compressionSamples = map[string]string{
+	"json": `{"id":1234,"name":"encoji","tags":["unicode","steganography","cli"],"active":true,"metadata":{"created":"2024-01-01T00:00:00Z","owner":"azr4e1"}}`,
+	"source": `func (s smuggler) EncodeText(target string) (string, error) {
+	if len(target) == 0 {
+		return "", errors.New("target cannot be empty")
+	}
+	payload := s.clearPayload()
+	if len(payload) == 0 {
+		return "", errors.New("clear text cannot be empty")
+	}
+	return "", nil
+}`,
+	"prose": `Variation selectors are invisible Unicode codepoints normally used to pick a
+glyph variant for the preceding character. Because they carry no visible
+rendering of their own, a string of them can ride along after an ordinary
+emoji or letter without changing how the text looks on screen.`,
+}
+
+// BenchmarkEmittedRuneCount reports how many runes each compression
+// algorithm emits for representative payloads, so the reduction from
+// compressing before encoding is visible in `go test -bench`.
+func BenchmarkEmittedRuneCount(b *testing.B) {
+	algos := []Algo{CompressNone, CompressGzip, CompressZstd}
+
+	for name, sample := range compressionSamples {
+		for _, algo := range algos {
+			b.Run(name+"/"+algoName(algo), func(b *testing.B) {
+				s, err := NewSmuggler(WithClearText(sample), WithCompression(algo))
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				var runes int
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					encoded, err := s.EncodeText("🙂")
+					if err != nil {
+						b.Fatal(err)
+					}
+					runes = len([]rune(encoded))
+				}
+				b.ReportMetric(float64(runes), "runes")
+			})
+		}
+	}
+}
+
+func TestCompressDecompressRoundTrip(t *testing.T) {
+	original := []byte(compressionSamples["prose"])
+
+	for _, algo := range []Algo{CompressGzip, CompressZstd} {
+		compressed, err := compressPayload(algo, original)
+		if err != nil {
+			t.Fatalf("%s: compressPayload: %v", algoName(algo), err)
+		}
+		decompressed, err := decompressPayload(compressed)
+		if err != nil {
+			t.Fatalf("%s: decompressPayload: %v", algoName(algo), err)
+		}
+		if !bytes.Equal(decompressed, original) {
+			t.Errorf("%s: got %q, want %q", algoName(algo), decompressed, original)
+		}
+	}
+}
+
+func TestCompressNoneRoundTrip(t *testing.T) {
+	original := []byte("a plain, never-compressed payload")
+
+	tagged, err := compressPayload(CompressNone, original)
+	if err != nil {
+		t.Fatalf("compressPayload: %v", err)
+	}
+	decompressed, err := decompressPayload(tagged)
+	if err != nil {
+		t.Fatalf("decompressPayload: %v", err)
+	}
+	if !bytes.Equal(decompressed, original) {
+		t.Errorf("got %q, want %q", decompressed, original)
+	}
+}
+
+// TestEncodeDecodeUncompressedPayloadCollidingWithOldMagic guards against a
+// regression where uncompressed clear text starting with the bytes of a
+// since-removed magic string was mistaken for a compressed envelope on
+// decode.
+func TestEncodeDecodeUncompressedPayloadCollidingWithOldMagic(t *testing.T) {
+	payload := "ENCJC this payload just happens to start with the old magic bytes"
+
+	s, err := NewSmuggler(WithClearText(payload))
+	if err != nil {
+		t.Fatalf("NewSmuggler: %v", err)
+	}
+
+	encoded, err := s.EncodeText("🙂")
+	if err != nil {
+		t.Fatalf("EncodeText: %v", err)
+	}
+
+	decoded, err := s.DecodeText(encoded)
+	if err != nil {
+		t.Fatalf("DecodeText: %v", err)
+	}
+	if decoded != payload {
+		t.Errorf("got %q, want %q", decoded, payload)
+	}
+}
+
+func algoName(a Algo) string {
+	switch a {
+	case CompressGzip:
+		return "gzip"
+	case CompressZstd:
+		return "zstd"
+	default:
+		return "none"
+	}
+}
@@ -0,0 +1,113 @@
+package encoji
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/term"
+)
+
+// encryptedMagic prefixes every payload produced by WithPassword/WithPasswordPrompt
+// so DecodeText can recognize an encrypted envelope without being told in advance.
+const encryptedMagic = "ENCJ1"
+
+const (
+	saltSize  = 16
+	nonceSize = 12
+	keySize   = 32
+)
+
+var errWrongPassword = errors.New("encoji: authentication failed: wrong password")
+
+// deriveKey turns a passphrase and salt into a 32-byte AES-256 key using Argon2id.
+func deriveKey(password string, salt []byte) []byte {
+	return argon2.IDKey([]byte(password), salt, 1, 64*1024, 4, keySize)
+}
+
+// encryptPayload encrypts plainText with a key derived from password and returns
+// encryptedMagic || salt(16) || nonce(12) || ciphertext+tag.
+func encryptPayload(password string, plainText []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+
+	key := deriveKey(password, salt)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+
+	ciphertext := gcm.Seal(nil, nonce, plainText, nil)
+
+	out := make([]byte, 0, len(encryptedMagic)+saltSize+nonceSize+len(ciphertext))
+	out = append(out, encryptedMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	out = append(out, ciphertext...)
+	return out, nil
+}
+
+// decryptPayload reverses encryptPayload, returning errWrongPassword if the
+// GCM tag does not verify.
+func decryptPayload(password string, data []byte) ([]byte, error) {
+	if !hasEncryptedMagic(data) {
+		return nil, errors.New("encoji: not an encrypted payload")
+	}
+	data = data[len(encryptedMagic):]
+	if len(data) < saltSize+nonceSize {
+		return nil, errors.New("encoji: truncated encrypted payload")
+	}
+
+	salt, data := data[:saltSize], data[saltSize:]
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+
+	key := deriveKey(password, salt)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	plainText, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errWrongPassword
+	}
+	return plainText, nil
+}
+
+// hasEncryptedMagic reports whether data starts with the encrypted envelope's
+// magic prefix.
+func hasEncryptedMagic(data []byte) bool {
+	return len(data) >= len(encryptedMagic) && string(data[:len(encryptedMagic)]) == encryptedMagic
+}
+
+// promptPassword reads a password from the given file descriptor without
+// echoing it back, for use when -encrypt is set but no password was supplied
+// via flag or environment variable.
+func promptPassword(stderr io.Writer, fd int) (string, error) {
+	fmt.Fprint(stderr, "Password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(stderr)
+	if err != nil {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return string(password), nil
+}
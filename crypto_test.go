@@ -0,0 +1,50 @@
+package encoji
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	plainText := []byte("the quick brown fox jumps over the lazy dog")
+
+	encrypted, err := encryptPayload("correct horse battery staple", plainText)
+	if err != nil {
+		t.Fatalf("encryptPayload: %v", err)
+	}
+	if !hasEncryptedMagic(encrypted) {
+		t.Fatalf("encrypted payload missing %q magic prefix", encryptedMagic)
+	}
+
+	decrypted, err := decryptPayload("correct horse battery staple", encrypted)
+	if err != nil {
+		t.Fatalf("decryptPayload: %v", err)
+	}
+	if !bytes.Equal(decrypted, plainText) {
+		t.Errorf("got %q, want %q", decrypted, plainText)
+	}
+}
+
+func TestDecryptWrongPassword(t *testing.T) {
+	encrypted, err := encryptPayload("correct horse battery staple", []byte("top secret"))
+	if err != nil {
+		t.Fatalf("encryptPayload: %v", err)
+	}
+
+	if _, err := decryptPayload("wrong password", encrypted); !errors.Is(err, errWrongPassword) {
+		t.Errorf("got %v, want %v", err, errWrongPassword)
+	}
+}
+
+func TestDecryptCorruptedCiphertext(t *testing.T) {
+	encrypted, err := encryptPayload("correct horse battery staple", []byte("top secret"))
+	if err != nil {
+		t.Fatalf("encryptPayload: %v", err)
+	}
+	encrypted[len(encrypted)-1] ^= 0xFF
+
+	if _, err := decryptPayload("correct horse battery staple", encrypted); !errors.Is(err, errWrongPassword) {
+		t.Errorf("got %v, want %v", err, errWrongPassword)
+	}
+}
@@ -24,51 +24,208 @@ const (
 type option func(*smuggler) error
 
 type smuggler struct {
-	stdin     io.Reader
-	stdout    io.Writer
-	stderr    io.Writer
-	clearText string
-	encode    bool
+	stdin          io.Reader
+	stdout         io.Writer
+	stderr         io.Writer
+	clearText      string
+	clearBytes     []byte
+	clearBytesSet  bool
+	encode         bool
+	password       string
+	passwordSet    bool
+	promptOnDecode bool
+	spreadMode     SpreadMode
+	framing        Framing
+	raw            bool
+	decodeBinary   bool
+	compression    Algo
+	inspect        bool
+}
+
+// clearPayload returns the raw clear-text bytes to hide, preferring
+// clearBytes (set via WithClearBytes) over the legacy string-based
+// clearText so binary payloads never round-trip through a string.
+func (s smuggler) clearPayload() []byte {
+	if s.clearBytesSet {
+		return s.clearBytes
+	}
+	return []byte(s.clearText)
 }
 
 func (s smuggler) EncodeText(target string) (string, error) {
 	if len(target) == 0 {
 		return "", errors.New("target cannot be empty")
 	}
-	if len(s.clearText) == 0 {
+	payload := s.clearPayload()
+	if len(payload) == 0 {
 		return "", errors.New("clear text cannot be empty")
 	}
-	encodedText := encode(rune(target[0]), []byte(s.clearText))
-	if len(target) > 1 {
-		encodedText += target[1:]
+
+	if s.framing != FramingNone && !s.clearBytesSet {
+		unframed, err := unframe(s.framing, payload)
+		if err != nil {
+			return "", fmt.Errorf("decoding framed clear text: %w", err)
+		}
+		payload = unframed
+	}
+
+	compressed, err := compressPayload(s.compression, payload)
+	if err != nil {
+		return "", err
+	}
+	payload = compressed
+
+	if s.passwordSet {
+		encrypted, err := encryptPayload(s.password, payload)
+		if err != nil {
+			return "", fmt.Errorf("encrypting clear text: %w", err)
+		}
+		payload = encrypted
+	}
+
+	targetRunes := []rune(target)
+	slots, err := distribute(payload, s.spreadMode, len(targetRunes))
+	if err != nil {
+		return "", err
+	}
+
+	sb := new(strings.Builder)
+	for i, r := range targetRunes {
+		sb.WriteString(encode(r, slots[i]))
 	}
 
-	return encodedText, nil
+	return sb.String(), nil
 }
 
-func (s smuggler) DecodeText(target string) (string, error) {
+// decodePayload recovers the hidden, decrypted, unframed byte stream from
+// target, shared by DecodeText and DecodeBytes.
+func (s smuggler) decodePayload(target string) ([]byte, error) {
 	if len(target) == 0 {
-		return "", errors.New("target cannot be empty")
+		return nil, errors.New("target cannot be empty")
+	}
+
+	slots := parseCarrier(target)
+	mode := detectSpreadMode(slots)
+
+	payload, err := reconstruct(slots, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasEncryptedMagic(payload) {
+		password := s.password
+		if !s.passwordSet {
+			if !s.promptOnDecode {
+				return nil, errors.New("encoji: payload is encrypted but no password was provided")
+			}
+			password, err = promptPassword(s.stderr, int(os.Stdin.Fd()))
+			if err != nil {
+				return nil, err
+			}
+		}
+		payload, err = decryptPayload(password, payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	payload, err = decompressPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// DecodeText recovers the hidden payload as text. It currently mangles
+// binary data by trimming surrounding whitespace; use DecodeBytes when the
+// payload must come back byte-for-byte.
+func (s smuggler) DecodeText(target string) (string, error) {
+	payload, err := s.decodePayload(target)
+	if err != nil {
+		return "", err
+	}
+
+	if s.framing != FramingNone {
+		framed, err := frame(s.framing, payload)
+		if err != nil {
+			return "", err
+		}
+		return string(framed), nil
+	}
+
+	return strings.TrimSpace(string(payload)), nil
+}
+
+// DecodeBytes recovers the hidden payload verbatim, with no whitespace
+// trimming, for callers that need exact binary data back.
+func (s smuggler) DecodeBytes(target string) ([]byte, error) {
+	payload, err := s.decodePayload(target)
+	if err != nil {
+		return nil, err
 	}
-	return strings.TrimSpace(decode(target)), nil
+
+	if s.framing != FramingNone {
+		return frame(s.framing, payload)
+	}
+
+	return payload, nil
 }
 
 func (s smuggler) Run() error {
+	if s.raw {
+		blob, err := io.ReadAll(s.stdin)
+		if err != nil {
+			return err
+		}
+		return s.runTarget(string(blob))
+	}
+
 	scan := bufio.NewScanner(s.stdin)
 	for scan.Scan() {
-		target := scan.Text()
-		var res string
-		var err error
-		if s.encode {
-			res, err = s.EncodeText(target)
-		} else {
-			res, err = s.DecodeText(target)
+		if err := s.runTarget(scan.Text()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runTarget encodes or decodes a single target and writes the result to
+// s.stdout, honoring decodeBinary so recovered bytes can be written
+// verbatim instead of as a newline-terminated string.
+func (s smuggler) runTarget(target string) error {
+	if s.inspect {
+		report, err := s.Inspect(target)
+		if err != nil {
+			return err
 		}
+		fmt.Fprintln(s.stdout, report.String())
+		return nil
+	}
+
+	if s.encode {
+		res, err := s.EncodeText(target)
 		if err != nil {
 			return err
 		}
 		fmt.Fprintln(s.stdout, res)
+		return nil
+	}
+
+	if s.decodeBinary {
+		res, err := s.DecodeBytes(target)
+		if err != nil {
+			return err
+		}
+		_, err = s.stdout.Write(res)
+		return err
+	}
+
+	res, err := s.DecodeText(target)
+	if err != nil {
+		return err
 	}
+	fmt.Fprintln(s.stdout, res)
 	return nil
 }
 
@@ -84,6 +241,32 @@ func (s *smuggler) SetClearText(text string) {
 	s.clearText = text
 }
 
+func (s *smuggler) SetClearBytes(data []byte) {
+	s.clearBytes = data
+	s.clearBytesSet = true
+}
+
+func (s *smuggler) SetRaw(raw bool) {
+	s.raw = raw
+}
+
+func (s *smuggler) SetDecodeBinary(decodeBinary bool) {
+	s.decodeBinary = decodeBinary
+}
+
+func (s *smuggler) SetInspect(inspect bool) {
+	s.inspect = inspect
+}
+
+func (s *smuggler) SetPassword(password string) {
+	s.password = password
+	s.passwordSet = true
+}
+
+func (s *smuggler) SetPromptOnDecode(promptOnDecode bool) {
+	s.promptOnDecode = promptOnDecode
+}
+
 func (s *smuggler) SetIn(stdin io.Reader) {
 	s.stdin = stdin
 }
@@ -169,6 +352,24 @@ func WithClearFile(file string) option {
 	}
 }
 
+// WithClearBinaryFile sets the clear text to hide from the raw bytes of
+// file, without the UTF-8 assumptions WithClearFile's string conversion
+// implies.
+func WithClearBinaryFile(file string) option {
+	return func(s *smuggler) error {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		s.SetClearBytes(data)
+		return nil
+	}
+}
+
 func WithEncodeFlag(f bool) option {
 	return func(s *smuggler) error {
 		s.encode = f
@@ -176,10 +377,73 @@ func WithEncodeFlag(f bool) option {
 	}
 }
 
+// WithClearBytes sets the clear text to hide from a raw byte slice,
+// bypassing the string conversion WithClearText/WithClearFile rely on so
+// arbitrary binary payloads (NUL bytes, invalid UTF-8, ...) survive intact.
+func WithClearBytes(data []byte) option {
+	return func(s *smuggler) error {
+		s.SetClearBytes(data)
+		return nil
+	}
+}
+
+// WithRaw makes Run read stdin as a single blob instead of scanning it
+// line by line, so a binary carrier isn't corrupted by line splitting.
+func WithRaw(raw bool) option {
+	return func(s *smuggler) error {
+		s.SetRaw(raw)
+		return nil
+	}
+}
+
+// WithDecodeBinary makes Run write the recovered payload verbatim to
+// stdout, with no whitespace trimming and no trailing newline.
+func WithDecodeBinary(decodeBinary bool) option {
+	return func(s *smuggler) error {
+		s.SetDecodeBinary(decodeBinary)
+		return nil
+	}
+}
+
+// WithPassword configures the smuggler to transparently encrypt the payload
+// on encode, and to require (and verify) the same password on decode.
+func WithPassword(password string) option {
+	return func(s *smuggler) error {
+		s.SetPassword(password)
+		return nil
+	}
+}
+
+// WithPasswordPrompt reads a password from the terminal without echoing it,
+// so it never ends up in shell history or process listings.
+func WithPasswordPrompt() option {
+	return func(s *smuggler) error {
+		password, err := promptPassword(s.stderr, int(os.Stdin.Fd()))
+		if err != nil {
+			return err
+		}
+		s.SetPassword(password)
+		return nil
+	}
+}
+
+// WithPasswordPromptOnDecode defers the password prompt until decode
+// actually finds an encrypted carrier, so decoding a plain (unencrypted)
+// carrier never asks for a password, and decoding an encrypted one
+// prompts for it instead of failing when -password/ENCOJI_PASSWORD was
+// not given.
+func WithPasswordPromptOnDecode() option {
+	return func(s *smuggler) error {
+		s.SetPromptOnDecode(true)
+		return nil
+	}
+}
+
 func Main(stdin, stdout, stderr io.ReadWriter) int {
 	flag.Usage = func() {
-		fmt.Fprintf(stderr, "Usage: %s [-encode string | -encodefile filepath | -decode] [stdin]\n", os.Args[0])
-		fmt.Fprintln(stderr, "Encode/decode text using unicode variation selectors\n")
+		fmt.Fprintf(stderr, "Usage: %s [-encode string | -encodefile filepath | -encodebinary filepath | -decode | -decodebinary | -inspect] [stdin]\n", os.Args[0])
+		fmt.Fprintln(stderr, "Encode/decode text using unicode variation selectors")
+		fmt.Fprintln(stderr)
 		fmt.Fprintln(stderr, "Flags:")
 		flag.PrintDefaults()
 	}
@@ -187,13 +451,29 @@ func Main(stdin, stdout, stderr io.ReadWriter) int {
 	encodeFile := flag.String("encodefile", "", "smuggle data from file within provided text")
 	decodeMode := flag.Bool("decode", false, "decode smuggled data")
 	version := flag.Bool("version", false, "print version")
+	encryptMode := flag.Bool("encrypt", false, "encrypt the payload with a password before hiding it")
+	passwordFlag := flag.String("password", "", "password for encryption/decryption (prefer -encrypt's prompt or the ENCOJI_PASSWORD env var, so the password doesn't end up in shell history)")
+	spreadFlag := flag.String("spread", "first", "how to distribute payload bytes across target runes: first, even, perrune, perword")
+	encodeBinaryFile := flag.String("encodebinary", "", "smuggle raw binary data from file within provided text")
+	decodeBinaryMode := flag.Bool("decodebinary", false, "decode smuggled data and write the recovered bytes verbatim to stdout")
+	rawFlag := flag.Bool("raw", false, "read stdin as a single blob instead of scanning it line by line (use for binary carriers)")
+	framingFlag := flag.String("framing", "none", "keep the recovered payload printable: none, base64, hex")
+	compressFlag := flag.String("compress", "none", "compress the clear text before hiding it: gzip, zstd, none")
+	inspectMode := flag.Bool("inspect", false, "print a debug report of the payload hidden in the provided text")
 	flag.Parse()
 
-	if !(*decodeMode) && *encodeMode == "" && *encodeFile == "" && !(*version) {
+	if !(*decodeMode) && *encodeMode == "" && *encodeFile == "" && !(*version) && *encodeBinaryFile == "" && !(*decodeBinaryMode) && !(*inspectMode) {
 		flag.Usage()
 		return MissingInputError
 	}
-	if flag.NFlag() > 1 {
+	modeFlagCount := 0
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "encode", "encodefile", "decode", "version", "encodebinary", "decodebinary", "inspect":
+			modeFlagCount++
+		}
+	})
+	if modeFlagCount > 1 {
 		fmt.Fprintln(stderr, "Error: too many flags provided")
 		flag.Usage()
 		return TooManyInputsError
@@ -205,11 +485,51 @@ func Main(stdin, stdout, stderr io.ReadWriter) int {
 	}
 
 	var encodeOption = WithClearText(*encodeMode)
-	if *encodeMode == "" && *encodeFile != "" {
+	switch {
+	case *encodeMode == "" && *encodeFile != "":
 		encodeOption = WithClearFile(*encodeFile)
+	case *encodeMode == "" && *encodeFile == "" && *encodeBinaryFile != "":
+		encodeOption = WithClearBinaryFile(*encodeBinaryFile)
 	}
 
-	s, err := NewSmuggler(WithInputFromArgs(flag.Args()), WithOutput(stdout), WithError(stderr), WithEncodeFlag(!(*decodeMode)), encodeOption)
+	spreadMode, err := parseSpreadMode(*spreadFlag)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return ExecutionError
+	}
+	framingMode, err := parseFraming(*framingFlag)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return ExecutionError
+	}
+	compressAlgo, err := parseAlgo(*compressFlag)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return ExecutionError
+	}
+
+	decode := *decodeMode || *decodeBinaryMode
+	opts := []option{
+		WithInputFromArgs(flag.Args()), WithOutput(stdout), WithError(stderr),
+		WithEncodeFlag(!decode), encodeOption, WithSpread(spreadMode),
+		WithFraming(framingMode), WithRaw(*rawFlag), WithDecodeBinary(*decodeBinaryMode),
+		WithCompression(compressAlgo), WithInspect(*inspectMode),
+	}
+
+	password := *passwordFlag
+	if password == "" {
+		password = os.Getenv("ENCOJI_PASSWORD")
+	}
+	switch {
+	case password != "":
+		opts = append(opts, WithPassword(password))
+	case *encryptMode && !decode:
+		opts = append(opts, WithPasswordPrompt())
+	case decode:
+		opts = append(opts, WithPasswordPromptOnDecode())
+	}
+
+	s, err := NewSmuggler(opts...)
 	if err != nil {
 		fmt.Fprintln(stderr, "Error:", err)
 		return ExecutionError
@@ -234,16 +554,6 @@ func byteToVariationSelector(b byte) rune {
 	return r
 }
 
-func encode(base rune, sentence []byte) string {
-	s := new(strings.Builder)
-	s.WriteRune(base)
-	for _, b := range sentence {
-		s.WriteRune(byteToVariationSelector(b))
-	}
-
-	return s.String()
-}
-
 func variationSelectorToByte(vs rune) (byte, error) {
 	varSel := uint32(vs)
 	var range1S, range1E uint32 = 0xFE00, 0xFE0F
@@ -260,16 +570,3 @@ func variationSelectorToByte(vs rune) (byte, error) {
 	}
 	return 0, errors.New("couldn't decode")
 }
-
-func decode(varSels string) string {
-	message := new(strings.Builder)
-	for _, vs := range varSels {
-		b, err := variationSelectorToByte(vs)
-		if err == nil {
-			message.WriteByte(b)
-		} else {
-			message.WriteByte('\n')
-		}
-	}
-	return message.String()
-}
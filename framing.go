@@ -0,0 +1,75 @@
+package encoji
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// Framing controls how clear text/recovered payloads are represented at the
+// program's edges (CLI flags, stdout) so that binary data can round-trip
+// through text-only channels without corruption.
+type Framing int
+
+const (
+	// FramingNone passes the payload through unchanged.
+	FramingNone Framing = iota
+	// FramingBase64 expects/produces standard base64 text.
+	FramingBase64
+	// FramingHex expects/produces lowercase hex text.
+	FramingHex
+)
+
+// unframe reverses the given framing, turning printable text supplied as
+// clear text back into the raw bytes to hide.
+func unframe(f Framing, data []byte) ([]byte, error) {
+	switch f {
+	case FramingNone:
+		return data, nil
+	case FramingBase64:
+		return base64.StdEncoding.DecodeString(string(data))
+	case FramingHex:
+		return hex.DecodeString(string(data))
+	default:
+		return nil, fmt.Errorf("encoji: unknown framing %d", f)
+	}
+}
+
+// frame applies the given framing to a recovered payload so it can be
+// printed safely.
+func frame(f Framing, data []byte) ([]byte, error) {
+	switch f {
+	case FramingNone:
+		return data, nil
+	case FramingBase64:
+		return []byte(base64.StdEncoding.EncodeToString(data)), nil
+	case FramingHex:
+		return []byte(hex.EncodeToString(data)), nil
+	default:
+		return nil, fmt.Errorf("encoji: unknown framing %d", f)
+	}
+}
+
+// parseFraming parses the -framing flag value accepted by Main.
+func parseFraming(name string) (Framing, error) {
+	switch name {
+	case "", "none":
+		return FramingNone, nil
+	case "base64":
+		return FramingBase64, nil
+	case "hex":
+		return FramingHex, nil
+	default:
+		return FramingNone, fmt.Errorf("encoji: unknown framing %q", name)
+	}
+}
+
+// WithFraming configures how clear text is decoded before hiding and how
+// the recovered payload is encoded before being returned, so it can be
+// kept printable even when the underlying data is binary.
+func WithFraming(f Framing) option {
+	return func(s *smuggler) error {
+		s.framing = f
+		return nil
+	}
+}
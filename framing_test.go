@@ -0,0 +1,103 @@
+package encoji
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+func TestFramingRoundTripBase64(t *testing.T) {
+	raw := []byte{0x00, 0x01, 0xFF, 0xFE, 'h', 'i'}
+	framedClearText := base64.StdEncoding.EncodeToString(raw)
+
+	s, err := NewSmuggler(WithClearText(framedClearText), WithFraming(FramingBase64))
+	if err != nil {
+		t.Fatalf("NewSmuggler: %v", err)
+	}
+
+	encoded, err := s.EncodeText("🙂")
+	if err != nil {
+		t.Fatalf("EncodeText: %v", err)
+	}
+
+	decodedText, err := s.DecodeText(encoded)
+	if err != nil {
+		t.Fatalf("DecodeText: %v", err)
+	}
+	if decodedText != framedClearText {
+		t.Errorf("DecodeText: got %q, want %q", decodedText, framedClearText)
+	}
+
+	decodedBytes, err := s.DecodeBytes(encoded)
+	if err != nil {
+		t.Fatalf("DecodeBytes: %v", err)
+	}
+	if string(decodedBytes) != framedClearText {
+		t.Errorf("DecodeBytes: got %q, want %q", decodedBytes, framedClearText)
+	}
+}
+
+func TestFramingRoundTripHex(t *testing.T) {
+	raw := []byte{0x00, 0x01, 0xFF, 0xFE, 'h', 'i'}
+	framedClearText := hex.EncodeToString(raw)
+
+	s, err := NewSmuggler(WithClearText(framedClearText), WithFraming(FramingHex))
+	if err != nil {
+		t.Fatalf("NewSmuggler: %v", err)
+	}
+
+	encoded, err := s.EncodeText("🙂")
+	if err != nil {
+		t.Fatalf("EncodeText: %v", err)
+	}
+
+	decodedText, err := s.DecodeText(encoded)
+	if err != nil {
+		t.Fatalf("DecodeText: %v", err)
+	}
+	if decodedText != framedClearText {
+		t.Errorf("DecodeText: got %q, want %q", decodedText, framedClearText)
+	}
+}
+
+func TestFramingNotAppliedToClearBytes(t *testing.T) {
+	raw := []byte{0x00, 0x01, 0xFF, 0xFE, 'h', 'i'}
+
+	s, err := NewSmuggler(WithClearBytes(raw), WithFraming(FramingBase64))
+	if err != nil {
+		t.Fatalf("NewSmuggler: %v", err)
+	}
+
+	encoded, err := s.EncodeText("🙂")
+	if err != nil {
+		t.Fatalf("EncodeText: %v", err)
+	}
+
+	decoded, err := s.DecodeBytes(encoded)
+	if err != nil {
+		t.Fatalf("DecodeBytes: %v", err)
+	}
+	want := base64.StdEncoding.EncodeToString(raw)
+	if string(decoded) != want {
+		t.Errorf("got %q, want %q", decoded, want)
+	}
+}
+
+func TestUnframeRoundTrip(t *testing.T) {
+	raw := []byte{0x00, 0x01, 0xFF, 0xFE, 'h', 'i'}
+
+	for _, f := range []Framing{FramingBase64, FramingHex} {
+		framed, err := frame(f, raw)
+		if err != nil {
+			t.Fatalf("frame(%d): %v", f, err)
+		}
+		unframed, err := unframe(f, framed)
+		if err != nil {
+			t.Fatalf("unframe(%d): %v", f, err)
+		}
+		if !bytes.Equal(unframed, raw) {
+			t.Errorf("framing %d: got %q, want %q", f, unframed, raw)
+		}
+	}
+}
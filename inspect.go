@@ -0,0 +1,125 @@
+package encoji
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// RuneInspection is the audit info Inspect gathers for a single base rune
+// of a carrier string.
+type RuneInspection struct {
+	Rune         rune
+	Codepoint    string
+	VSCount      int
+	VSCodepoints []string
+	Dump         string
+}
+
+// InspectReport summarizes what Inspect found hidden in a carrier string.
+type InspectReport struct {
+	TotalHiddenBytes int
+	CarrierRunesUsed int
+	PayloadKind      string
+	Runes            []RuneInspection
+}
+
+// String renders the report the way -inspect prints it: a summary
+// followed by one entry per base rune that carries a payload.
+func (r InspectReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Hidden payload: %d byte(s) across %d carrier rune(s)\n", r.TotalHiddenBytes, r.CarrierRunesUsed)
+	fmt.Fprintf(&sb, "Payload looks like: %s\n", r.PayloadKind)
+
+	for _, ri := range r.Runes {
+		if ri.VSCount == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\nrune %q (%s): %d variation selector(s) [%s]\n", ri.Rune, ri.Codepoint, ri.VSCount, strings.Join(ri.VSCodepoints, " "))
+		sb.WriteString(ri.Dump)
+	}
+
+	return sb.String()
+}
+
+// Inspect walks target rune-by-rune and reports, for every base rune, how
+// many variation selectors trail it and what bytes they decode to, plus a
+// summary of the hidden payload as a whole. It's a debugging aid for
+// auditing suspicious text and validating that an encode produced what
+// was expected; unlike DecodeText it never requires a password.
+func (s smuggler) Inspect(target string) (InspectReport, error) {
+	if len(target) == 0 {
+		return InspectReport{}, errors.New("target cannot be empty")
+	}
+
+	walked := walkCarrier(target)
+	slots := make([][]byte, len(walked))
+	for i, w := range walked {
+		slots[i] = w.bytes
+	}
+	mode := detectSpreadMode(slots)
+
+	payload, err := reconstruct(slots, mode)
+	if err != nil {
+		return InspectReport{}, err
+	}
+
+	report := InspectReport{PayloadKind: classifyPayload(payload)}
+	for _, w := range walked {
+		report.TotalHiddenBytes += len(w.bytes)
+		if len(w.bytes) > 0 {
+			report.CarrierRunesUsed++
+		}
+
+		vsCodepoints := make([]string, len(w.vsRunes))
+		for i, vs := range w.vsRunes {
+			vsCodepoints[i] = fmt.Sprintf("%04X", vs)
+		}
+
+		report.Runes = append(report.Runes, RuneInspection{
+			Rune:         w.baseRune,
+			Codepoint:    fmt.Sprintf("U+%04X", w.baseRune),
+			VSCount:      len(w.vsRunes),
+			VSCodepoints: vsCodepoints,
+			Dump:         hex.Dump(w.bytes),
+		})
+	}
+
+	return report, nil
+}
+
+// classifyPayload guesses what kind of data a reconstructed-but-not-yet-
+// decrypted-or-decompressed payload holds, for InspectReport.PayloadKind.
+func classifyPayload(payload []byte) string {
+	switch {
+	case len(payload) == 0:
+		return "empty"
+	case hasEncryptedMagic(payload):
+		return "encrypted (password required)"
+	default:
+		switch Algo(payload[0]) {
+		case CompressGzip:
+			return "gzip-compressed"
+		case CompressZstd:
+			return "zstd-compressed"
+		case CompressNone:
+			if utf8.Valid(payload[1:]) {
+				return "utf-8 text"
+			}
+			return "binary"
+		default:
+			return "binary"
+		}
+	}
+}
+
+// WithInspect selects inspect mode: Run calls Inspect instead of
+// EncodeText/DecodeText for every target.
+func WithInspect(inspect bool) option {
+	return func(s *smuggler) error {
+		s.SetInspect(inspect)
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package encoji
+
+import "testing"
+
+func TestInspectReportsPlainPayload(t *testing.T) {
+	s, err := NewSmuggler(WithClearText("hello"))
+	if err != nil {
+		t.Fatalf("NewSmuggler: %v", err)
+	}
+
+	encoded, err := s.EncodeText("🙂")
+	if err != nil {
+		t.Fatalf("EncodeText: %v", err)
+	}
+
+	report, err := s.Inspect(encoded)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if report.PayloadKind != "utf-8 text" {
+		t.Errorf("PayloadKind = %q, want %q", report.PayloadKind, "utf-8 text")
+	}
+	// +1 for the 1-byte compression-algorithm tag compressPayload always
+	// writes, +1 for the 1-byte spread-mode tag distribute always writes
+	// ahead of the payload in slots[0].
+	want := len("hello") + 2
+	if report.TotalHiddenBytes != want {
+		t.Errorf("TotalHiddenBytes = %d, want %d", report.TotalHiddenBytes, want)
+	}
+	if report.CarrierRunesUsed != 1 {
+		t.Errorf("CarrierRunesUsed = %d, want 1", report.CarrierRunesUsed)
+	}
+}
+
+func TestInspectFlagsEncryptedPayload(t *testing.T) {
+	s, err := NewSmuggler(WithClearText("hello"), WithPassword("hunter2"))
+	if err != nil {
+		t.Fatalf("NewSmuggler: %v", err)
+	}
+
+	encoded, err := s.EncodeText("🙂")
+	if err != nil {
+		t.Fatalf("EncodeText: %v", err)
+	}
+
+	report, err := s.Inspect(encoded)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if report.PayloadKind != "encrypted (password required)" {
+		t.Errorf("PayloadKind = %q, want %q", report.PayloadKind, "encrypted (password required)")
+	}
+}
+
+func TestInspectFlagsCompressedPayload(t *testing.T) {
+	s, err := NewSmuggler(WithClearText("hello"), WithCompression(CompressGzip))
+	if err != nil {
+		t.Fatalf("NewSmuggler: %v", err)
+	}
+
+	encoded, err := s.EncodeText("🙂")
+	if err != nil {
+		t.Fatalf("EncodeText: %v", err)
+	}
+
+	report, err := s.Inspect(encoded)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if report.PayloadKind != "gzip-compressed" {
+		t.Errorf("PayloadKind = %q, want %q", report.PayloadKind, "gzip-compressed")
+	}
+}
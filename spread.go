@@ -0,0 +1,230 @@
+package encoji
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// SpreadMode controls how a payload's bytes are distributed across the
+// runes of the carrier string.
+type SpreadMode int
+
+const (
+	// SpreadFirst attaches the whole payload to the first rune of the
+	// target, leaving every other rune untouched. This is the original
+	// encoji behavior and remains the default.
+	SpreadFirst SpreadMode = iota
+	// SpreadEven splits the payload into as many equal-ish contiguous
+	// chunks as there are runes in the target, one chunk per rune.
+	SpreadEven
+	// SpreadPerRune distributes the payload one byte at a time, cycling
+	// through the target's runes.
+	SpreadPerRune
+	// SpreadPerWord distributes the payload one space-delimited word at a
+	// time (each word keeping its trailing space), cycling through the
+	// target's runes. Only the space byte splits words; tabs and newlines
+	// do not.
+	SpreadPerWord
+)
+
+// maxFramedChunk is the largest chunk distributeFramed can record the
+// length of in its 2-byte big-endian length prefix.
+const maxFramedChunk = 1<<16 - 1
+
+// distribute splits payload according to mode and returns one byte slice
+// per rune of the carrier (numSlots == the number of runes in target),
+// ready to be attached as that rune's variation-selector run. slots[0]
+// always starts with a 1-byte mode tag, even for SpreadFirst, so
+// reconstruct never has to guess the mode by sniffing the payload for a
+// magic prefix that arbitrary clear text could itself start with.
+func distribute(payload []byte, mode SpreadMode, numSlots int) ([][]byte, error) {
+	if numSlots < 1 {
+		return nil, errors.New("encoji: target has no runes to carry a payload")
+	}
+
+	slots := make([][]byte, numSlots)
+
+	switch mode {
+	case SpreadFirst:
+		slots[0] = payload
+	case SpreadEven:
+		chunkSize, remainder := len(payload)/numSlots, len(payload)%numSlots
+		offset := 0
+		for i := range slots {
+			size := chunkSize
+			if i < remainder {
+				size++
+			}
+			slots[i] = payload[offset : offset+size]
+			offset += size
+		}
+	case SpreadPerRune:
+		items := make([][]byte, len(payload))
+		for i, b := range payload {
+			items[i] = []byte{b}
+		}
+		if err := distributeFramed(items, slots); err != nil {
+			return nil, err
+		}
+	case SpreadPerWord:
+		items := bytes.SplitAfter(payload, []byte{' '})
+		if err := distributeFramed(items, slots); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("encoji: unknown spread mode %d", mode)
+	}
+
+	slots[0] = append([]byte{byte(mode)}, slots[0]...)
+	return slots, nil
+}
+
+// distributeFramed round-robins items across slots, prefixing each item
+// with a 2-byte big-endian length so reconstruct can tell where one item
+// ends and the next begins whenever a slot ends up carrying more than one.
+func distributeFramed(items [][]byte, slots [][]byte) error {
+	for i, item := range items {
+		if len(item) > maxFramedChunk {
+			return fmt.Errorf("encoji: chunk too large to spread (%d bytes, max %d)", len(item), maxFramedChunk)
+		}
+		slot := i % len(slots)
+		length := len(item)
+		slots[slot] = append(slots[slot], byte(length>>8), byte(length))
+		slots[slot] = append(slots[slot], item...)
+	}
+	return nil
+}
+
+// reconstruct reverses distribute, turning the per-rune byte slices
+// recovered by parseCarrier back into the original payload.
+func reconstruct(slots [][]byte, mode SpreadMode) ([]byte, error) {
+	switch mode {
+	case SpreadFirst:
+		if len(slots) == 0 {
+			return nil, nil
+		}
+		return slots[0], nil
+	case SpreadEven:
+		var buf bytes.Buffer
+		for _, slot := range slots {
+			buf.Write(slot)
+		}
+		return buf.Bytes(), nil
+	case SpreadPerRune, SpreadPerWord:
+		return reconstructFramed(slots)
+	default:
+		return nil, fmt.Errorf("encoji: unknown spread mode %d", mode)
+	}
+}
+
+func reconstructFramed(slots [][]byte) ([]byte, error) {
+	type item struct {
+		order int
+		data  []byte
+	}
+	var items []item
+
+	for slotIndex, raw := range slots {
+		lap := 0
+		for pos := 0; pos < len(raw); {
+			if pos+2 > len(raw) {
+				return nil, errors.New("encoji: truncated spread chunk")
+			}
+			length := int(raw[pos])<<8 | int(raw[pos+1])
+			pos += 2
+			if pos+length > len(raw) {
+				return nil, errors.New("encoji: truncated spread chunk")
+			}
+			items = append(items, item{order: lap*len(slots) + slotIndex, data: raw[pos : pos+length]})
+			pos += length
+			lap++
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].order < items[j].order })
+
+	var buf bytes.Buffer
+	for _, it := range items {
+		buf.Write(it.data)
+	}
+	return buf.Bytes(), nil
+}
+
+// carrierSlot is one base (non-variation-selector) rune of a carrier
+// string together with the variation-selector run trailing it.
+type carrierSlot struct {
+	baseRune rune
+	vsRunes  []rune
+	bytes    []byte
+}
+
+// walkCarrier walks target rune-by-rune and returns, in order, each base
+// (non-variation-selector) rune together with the variation-selector run
+// trailing it. Every rune of target starts a new slot except the
+// variation selectors themselves, which are collected into the preceding
+// one.
+func walkCarrier(target string) []carrierSlot {
+	var slots []carrierSlot
+	for _, r := range target {
+		if b, err := variationSelectorToByte(r); err == nil && len(slots) > 0 {
+			last := &slots[len(slots)-1]
+			last.vsRunes = append(last.vsRunes, r)
+			last.bytes = append(last.bytes, b)
+			continue
+		}
+		slots = append(slots, carrierSlot{baseRune: r})
+	}
+	return slots
+}
+
+// parseCarrier is walkCarrier stripped down to the byte runs DecodeText
+// needs to reconstruct the hidden payload.
+func parseCarrier(target string) [][]byte {
+	walked := walkCarrier(target)
+	slots := make([][]byte, len(walked))
+	for i, w := range walked {
+		slots[i] = w.bytes
+	}
+	return slots
+}
+
+// detectSpreadMode reads the 1-byte mode tag distribute always writes to
+// the front of slots[0] and strips it off. If slots[0] is empty (nothing
+// is hidden in the carrier), it reports SpreadFirst and leaves slots
+// untouched.
+func detectSpreadMode(slots [][]byte) SpreadMode {
+	if len(slots) == 0 || len(slots[0]) == 0 {
+		return SpreadFirst
+	}
+	mode := SpreadMode(slots[0][0])
+	slots[0] = slots[0][1:]
+	return mode
+}
+
+// parseSpreadMode parses the -spread flag value accepted by Main.
+func parseSpreadMode(name string) (SpreadMode, error) {
+	switch name {
+	case "", "first":
+		return SpreadFirst, nil
+	case "even":
+		return SpreadEven, nil
+	case "perrune":
+		return SpreadPerRune, nil
+	case "perword":
+		return SpreadPerWord, nil
+	default:
+		return SpreadFirst, fmt.Errorf("encoji: unknown spread mode %q", name)
+	}
+}
+
+// WithSpread configures how the payload is distributed across the
+// carrier's runes on encode. The default, SpreadFirst, matches encoji's
+// original behavior of attaching everything to the first rune.
+func WithSpread(mode SpreadMode) option {
+	return func(s *smuggler) error {
+		s.spreadMode = mode
+		return nil
+	}
+}
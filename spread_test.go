@@ -0,0 +1,83 @@
+package encoji
+
+import "testing"
+
+func TestEncodeDecodeSpreadModes(t *testing.T) {
+	payloads := []string{
+		"hello, world!",
+		"The quick brown fox jumps over the lazy dog.",
+		"",
+	}
+	targets := []string{
+		"a",
+		"hello",
+		"😀🎉🚀",
+		"日本語のテキスト",
+		"é́́", // combining marks stacked on a base rune
+		"a😀b日c",
+	}
+	modes := []SpreadMode{SpreadFirst, SpreadEven, SpreadPerRune, SpreadPerWord}
+
+	for _, mode := range modes {
+		for _, target := range targets {
+			for _, payload := range payloads {
+				if payload == "" {
+					continue
+				}
+				s, err := NewSmuggler(WithClearText(payload), WithSpread(mode))
+				if err != nil {
+					t.Fatalf("mode=%d target=%q: NewSmuggler: %v", mode, target, err)
+				}
+
+				encoded, err := s.EncodeText(target)
+				if err != nil {
+					t.Fatalf("mode=%d target=%q payload=%q: EncodeText: %v", mode, target, payload, err)
+				}
+
+				decoded, err := s.DecodeText(encoded)
+				if err != nil {
+					t.Fatalf("mode=%d target=%q payload=%q: DecodeText: %v", mode, target, payload, err)
+				}
+
+				if decoded != payload {
+					t.Errorf("mode=%d target=%q: got %q, want %q", mode, target, decoded, payload)
+				}
+			}
+		}
+	}
+}
+
+// TestEncodeDecodeSpreadFirstPayloadCollidingWithOldMagic guards against a
+// regression where SpreadFirst, then the header-less default, mistook clear
+// text starting with the bytes of a since-removed magic string for a
+// spread-mode header on decode.
+func TestEncodeDecodeSpreadFirstPayloadCollidingWithOldMagic(t *testing.T) {
+	payload := "ENCJS this payload just happens to start with the old magic bytes"
+
+	s, err := NewSmuggler(WithClearText(payload), WithSpread(SpreadFirst))
+	if err != nil {
+		t.Fatalf("NewSmuggler: %v", err)
+	}
+
+	encoded, err := s.EncodeText("🙂")
+	if err != nil {
+		t.Fatalf("EncodeText: %v", err)
+	}
+
+	decoded, err := s.DecodeText(encoded)
+	if err != nil {
+		t.Fatalf("DecodeText: %v", err)
+	}
+	if decoded != payload {
+		t.Errorf("got %q, want %q", decoded, payload)
+	}
+}
+
+func TestDistributeFramedRejectsOversizedChunk(t *testing.T) {
+	items := [][]byte{make([]byte, maxFramedChunk+1)}
+	slots := make([][]byte, 1)
+
+	if err := distributeFramed(items, slots); err == nil {
+		t.Fatal("expected an error for a chunk larger than maxFramedChunk, got nil")
+	}
+}
@@ -0,0 +1,95 @@
+package encoji
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// encoder streams a payload out as a base rune followed by one variation
+// selector per byte written to it.
+type encoder struct {
+	w         io.Writer
+	base      rune
+	wroteBase bool
+}
+
+// NewEncoder returns an io.WriteCloser that writes base once, on the
+// first Write, and then streams every subsequent byte to w as a
+// variation selector. Unlike encode, it never buffers the whole payload
+// in memory, so it composes with io.Copy, gzip.NewWriter,
+// cipher.StreamWriter and friends for gigabyte-scale payloads.
+func NewEncoder(w io.Writer, base rune) io.WriteCloser {
+	return &encoder{w: w, base: base}
+}
+
+func (e *encoder) Write(p []byte) (int, error) {
+	if !e.wroteBase {
+		if _, err := e.w.Write([]byte(string(e.base))); err != nil {
+			return 0, err
+		}
+		e.wroteBase = true
+	}
+
+	for i, b := range p {
+		if _, err := e.w.Write([]byte(string(byteToVariationSelector(b)))); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close writes base if no bytes were ever written to e, so an empty
+// payload still produces a valid (if unremarkable) carrier.
+func (e *encoder) Close() error {
+	if e.wroteBase {
+		return nil
+	}
+	_, err := e.w.Write([]byte(string(e.base)))
+	e.wroteBase = true
+	return err
+}
+
+// decoder streams a payload in by reading runes from r on demand,
+// decoding variation selectors to bytes and silently skipping everything
+// else (the base rune, or any other carrier text).
+type decoder struct {
+	br *bufio.Reader
+}
+
+// NewDecoder returns an io.Reader that yields the bytes hidden as
+// variation selectors in r, without requiring the whole carrier to be
+// read into memory first.
+func NewDecoder(r io.Reader) io.Reader {
+	return &decoder{br: bufio.NewReader(r)}
+}
+
+func (d *decoder) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		r, _, err := d.br.ReadRune()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		b, err := variationSelectorToByte(r)
+		if err != nil {
+			continue
+		}
+		p[n] = b
+		n++
+	}
+	return n, nil
+}
+
+// encode is a thin wrapper over NewEncoder for callers that want the
+// whole result as a string.
+func encode(base rune, sentence []byte) string {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, base)
+	_, _ = enc.Write(sentence)
+	_ = enc.Close()
+	return buf.String()
+}
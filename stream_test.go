@@ -0,0 +1,46 @@
+package encoji
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestEncoderDecoderRoundTrip(t *testing.T) {
+	payload := []byte("the quick brown fox jumps over the lazy dog")
+
+	var carrier bytes.Buffer
+	enc := NewEncoder(&carrier, '🙂')
+	if _, err := io.Copy(enc, bytes.NewReader(payload)); err != nil {
+		t.Fatalf("writing to encoder: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("closing encoder: %v", err)
+	}
+
+	var recovered bytes.Buffer
+	if _, err := io.Copy(&recovered, NewDecoder(&carrier)); err != nil {
+		t.Fatalf("reading from decoder: %v", err)
+	}
+
+	if !bytes.Equal(recovered.Bytes(), payload) {
+		t.Errorf("got %q, want %q", recovered.Bytes(), payload)
+	}
+}
+
+func TestDecoderSkipsNonVariationSelectorRunes(t *testing.T) {
+	carrier := io.MultiReader(
+		bytes.NewReader([]byte("some plain text 🎉 around the payload ")),
+		bytes.NewReader([]byte(encode('🙂', []byte("hidden")))),
+		bytes.NewReader([]byte(" and some more text")),
+	)
+
+	var recovered bytes.Buffer
+	if _, err := io.Copy(&recovered, NewDecoder(carrier)); err != nil {
+		t.Fatalf("reading from decoder: %v", err)
+	}
+
+	if recovered.String() != "hidden" {
+		t.Errorf("got %q, want %q", recovered.String(), "hidden")
+	}
+}